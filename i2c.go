@@ -13,6 +13,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"syscall"
 )
 
@@ -21,37 +22,34 @@ import (
 func NOOPDebugf(string, ...interface{}) {}
 
 // Device is a connection to a device on the I²C bus. It contains a file handle
-// to a specific device address on a numbered I²C bus.
+// to a specific device address on a numbered I²C bus. Device is safe for
+// concurrent use, except that SetDebugf is not synchronized with its other
+// methods.
 type Device struct {
-	addr   uint8
+	addr   Address
 	bus    int
 	rc     *os.File
 	debugf func(string, ...interface{})
+
+	// mu guards rc for a Device that owns its file descriptor outright.
+	// shared, when non-nil, means the Device instead shares a Bus's file
+	// descriptor and lock; see lock/unlock in i2c_bus.go.
+	mu     sync.Mutex
+	shared *Bus
 }
 
 // New opens a new file handle on the provided I²C bus, making an ioctl call
-// to request read/write access to the device at the specified address.
+// to request read/write access to the device at the specified 7-bit
+// address. Use NewTenBit for a device with a 10-bit address.
 //
 // Most interactions start with either reads or writes at a specific register
 // address. See ReadReg and WriteReg.
 func New(bus int, addr uint8) (*Device, error) {
-	f, err := os.OpenFile(fmt.Sprintf("/dev/i2c-%d", bus), os.O_RDWR, 0600)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := ioctl(f.Fd(), i2cSlave, uintptr(addr)); err != nil {
-		return nil, err
-	}
-
-	i := &Device{
-		rc:     f,
-		bus:    bus,
-		addr:   addr,
-		debugf: NOOPDebugf,
-	}
+	return newDevice(bus, Addr7(addr), Options{})
+}
 
-	return i, nil
+func openBus(bus int) (*os.File, error) {
+	return os.OpenFile(fmt.Sprintf("/dev/i2c-%d", bus), os.O_RDWR, 0600)
 }
 
 // SetDebugf sets a formatted debug function, which can be used to hook in to
@@ -62,11 +60,18 @@ func (d *Device) SetDebugf(debugf func(format string, args ...interface{})) {
 
 // Bus return bus number to create this device.
 func (d *Device) Bus() int {
+	d.fieldMu().Lock()
+	defer d.fieldMu().Unlock()
+
 	return d.bus
 }
 
-// Addr returns the device's address on the I²C bus.
-func (d *Device) Addr() uint8 {
+// Addr returns the device's full address on the I²C bus, including whether
+// it is a 10-bit address.
+func (d *Device) Addr() Address {
+	d.fieldMu().Lock()
+	defer d.fieldMu().Unlock()
+
 	return d.addr
 }
 
@@ -81,6 +86,12 @@ func (d *Device) Write(p []byte) (int, error) {
 		return 0, errors.New("minimum message length 1")
 	}
 
+	if err := d.lock(); err != nil {
+		d.unlock()
+		return 0, err
+	}
+	defer d.unlock()
+
 	d.debugf("Write %d bytes: [%+v]", n, hex.EncodeToString(p))
 
 	return d.rc.Write(p)
@@ -116,6 +127,12 @@ func (d *Device) WriteReg(p []byte, reg byte) (int, error) {
 
 // Read satisfies io.Reader, reading data from the I2C device.
 func (d *Device) Read(p []byte) (int, error) {
+	if err := d.lock(); err != nil {
+		d.unlock()
+		return 0, err
+	}
+	defer d.unlock()
+
 	n, err := d.rc.Read(p)
 	if err != nil {
 		return n, err
@@ -128,42 +145,55 @@ func (d *Device) Read(p []byte) (int, error) {
 // ReadReg reads I2C device data at the specified register address into the
 // buffer provided. This expects you to right-size the buffer so that it only
 // reads the appropriate amount of data.
+//
+// The register write and the following read happen under a single lock, so
+// a Device sharing a Bus with other Devices can't have its slave address
+// reselected between the two.
 func (d *Device) ReadReg(p []byte, reg byte) (int, error) {
 	d.debugf("Reading %d bytes from register 0x%0X", len(p), reg)
 
-	_, err := d.WriteByte(reg)
-	if err != nil {
+	if err := d.lock(); err != nil {
+		d.unlock()
 		return 0, err
 	}
+	defer d.unlock()
 
-	n, err := d.Read(p)
+	if _, err := d.rc.Write([]byte{reg}); err != nil {
+		return 0, err
+	}
+
+	n, err := d.rc.Read(p)
 	if err != nil {
 		return n, err
 	}
 
+	d.debugf("Read %d bytes: [%+v]", n, hex.EncodeToString(p[:n]))
 	return n, nil
 }
 
-// Close I²C file handle.
+// Close I²C file handle. A Device opened from a Bus (via Bus.Open) does not
+// own its file descriptor, so Close on it only forgets the Device's own
+// state; close the Bus itself once every Device sharing it is done.
 func (d *Device) Close() error {
-	err := d.rc.Close()
+	d.fieldMu().Lock()
 	d.bus = 0
-	d.addr = 0
+	d.addr = Address{}
+	shared := d.shared
+	d.fieldMu().Unlock()
 
-	return err
+	if shared != nil {
+		return nil
+	}
+
+	return d.rc.Close()
 }
 
 // ReadRegU8 reads byte from I2C device register specified in reg.
 // SMBus (System Management Bus) protocol over I2C.
 func (d *Device) ReadRegU8(reg byte) (byte, error) {
-	_, err := d.WriteByte(reg)
-	if err != nil {
-		return 0, err
-	}
-
 	buf := make([]byte, 1)
 
-	_, err = d.Read(buf)
+	_, err := d.ReadReg(buf, reg)
 	if err != nil {
 		return 0, err
 	}
@@ -190,14 +220,9 @@ func (d *Device) WriteRegU8(reg byte, value byte) error {
 // from I2C device starting from address specified in reg.
 // SMBus (System Management Bus) protocol over I2C.
 func (d *Device) ReadRegU16BE(reg byte) (uint16, error) {
-	_, err := d.WriteByte(reg)
-	if err != nil {
-		return 0, err
-	}
-
 	buf := make([]byte, 2)
 
-	_, err = d.Read(buf)
+	_, err := d.ReadReg(buf, reg)
 	if err != nil {
 		return 0, err
 	}
@@ -227,14 +252,9 @@ func (d *Device) ReadRegU16LE(reg byte) (uint16, error) {
 // from I2C device starting from address specified in reg.
 // SMBus (System Management Bus) protocol over I2C.
 func (d *Device) ReadRegS16BE(reg byte) (int16, error) {
-	_, err := d.WriteByte(reg)
-	if err != nil {
-		return 0, err
-	}
-
 	buf := make([]byte, 2)
 
-	_, err = d.Read(buf)
+	_, err := d.ReadReg(buf, reg)
 	if err != nil {
 		return 0, err
 	}
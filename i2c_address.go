@@ -0,0 +1,81 @@
+package i2c
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Address identifies an I²C slave, either a plain 7-bit address or, when
+// constructed with TenBitAddr, a 10-bit address.
+type Address struct {
+	raw    uint16
+	tenBit bool
+}
+
+// Addr7 wraps addr as a standard 7-bit I²C address.
+func Addr7(addr uint8) Address {
+	return Address{raw: uint16(addr)}
+}
+
+// TenBitAddr wraps addr as a 10-bit I²C address, suitable for NewTenBit or
+// for a Message in a Tx.
+func TenBitAddr(addr uint16) Address {
+	return Address{raw: addr, tenBit: true}
+}
+
+// Raw returns the numeric value of the address.
+func (a Address) Raw() uint16 {
+	return a.raw
+}
+
+// IsTenBit reports whether a is a 10-bit address.
+func (a Address) IsTenBit() bool {
+	return a.tenBit
+}
+
+// NewTenBit is like New, but opens the device at a 10-bit address instead
+// of a 7-bit one. It fails if the adapter's I2C_FUNCS result lacks
+// I2C_FUNC_10BIT_ADDR.
+func NewTenBit(bus int, addr uint16) (*Device, error) {
+	return newDevice(bus, TenBitAddr(addr), Options{})
+}
+
+func newDevice(bus int, addr Address, opts Options) (*Device, error) {
+	f, err := openBus(bus)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Device{
+		rc:     f,
+		bus:    bus,
+		debugf: NOOPDebugf,
+	}
+
+	if addr.tenBit {
+		funcs, err := d.Funcs()
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("querying adapter functionality: %v", err)
+		}
+
+		if !funcs.Has(FuncTenBitAddr) {
+			f.Close()
+			return nil, errors.New("adapter does not support 10-bit addressing")
+		}
+
+		if err := ioctl(f.Fd(), i2cTenbit, 1); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	if err := ioctl(f.Fd(), slaveIoctl(opts), uintptr(addr.raw)); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	d.addr = addr
+
+	return d, nil
+}
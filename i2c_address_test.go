@@ -0,0 +1,27 @@
+package i2c
+
+import "testing"
+
+func TestAddr7(t *testing.T) {
+	a := Addr7(0x50)
+
+	if a.Raw() != 0x50 {
+		t.Fatalf("Raw() = 0x%0X, want 0x50", a.Raw())
+	}
+
+	if a.IsTenBit() {
+		t.Fatal("IsTenBit() = true for a 7-bit address")
+	}
+}
+
+func TestTenBitAddr(t *testing.T) {
+	a := TenBitAddr(0x3FF)
+
+	if a.Raw() != 0x3FF {
+		t.Fatalf("Raw() = 0x%0X, want 0x3FF", a.Raw())
+	}
+
+	if !a.IsTenBit() {
+		t.Fatal("IsTenBit() = false for a 10-bit address")
+	}
+}
@@ -0,0 +1,124 @@
+package i2c
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Bus owns a single /dev/i2c-N file descriptor and lets multiple Device
+// handles share it safely. A Linux I²C adapter has exactly one active
+// slave address at a time, so two Devices on two different addresses that
+// each hold their own file descriptor to the same adapter can race on the
+// I2C_SLAVE ioctl. A Bus serializes access with a mutex and reselects a
+// Device's address immediately before every operation, the same approach
+// periph.io and embd use.
+type Bus struct {
+	mu  sync.Mutex
+	rc  *os.File
+	bus int
+}
+
+// OpenBus opens the given I²C bus number for sharing across multiple
+// Devices. Close it once every Device opened from it is done.
+func OpenBus(bus int) (*Bus, error) {
+	f, err := openBus(bus)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bus{rc: f, bus: bus}, nil
+}
+
+// Open returns a Device bound to the 7-bit address addr that shares this
+// Bus's file descriptor and lock. Devices opened from the same Bus may be
+// used concurrently from multiple goroutines; operations against different
+// Devices are serialized, but an operation is never interleaved with
+// another Device's I2C_SLAVE reselect mid-transaction.
+func (b *Bus) Open(addr uint8) (*Device, error) {
+	return b.OpenAddr(Addr7(addr))
+}
+
+// OpenAddr is like Open, but accepts an Address so a 10-bit address can
+// also share a Bus. It fails if addr is a 10-bit address and the adapter's
+// I2C_FUNCS result lacks I2C_FUNC_10BIT_ADDR.
+func (b *Bus) OpenAddr(addr Address) (*Device, error) {
+	d := &Device{
+		bus:    b.bus,
+		addr:   addr,
+		rc:     b.rc,
+		debugf: NOOPDebugf,
+		shared: b,
+	}
+
+	if addr.tenBit {
+		funcs, err := d.Funcs()
+		if err != nil {
+			return nil, fmt.Errorf("querying adapter functionality: %v", err)
+		}
+
+		if !funcs.Has(FuncTenBitAddr) {
+			return nil, errors.New("adapter does not support 10-bit addressing")
+		}
+	}
+
+	return d, nil
+}
+
+// Close closes the underlying file descriptor. Every Device opened from
+// this Bus becomes unusable afterward.
+func (b *Bus) Close() error {
+	return b.rc.Close()
+}
+
+// fieldMu returns the mutex that guards d's fields and I/O: d.shared.mu for
+// a Device sharing a Bus, since every Device sharing that Bus must be kept
+// from reselecting the slave address out from under one another, or d.mu
+// for a Device that owns its file descriptor outright.
+func (d *Device) fieldMu() *sync.Mutex {
+	if d.shared != nil {
+		return &d.shared.mu
+	}
+
+	return &d.mu
+}
+
+// lock acquires exclusive access to d's underlying file descriptor for the
+// duration of one operation. When d was opened from a shared Bus, it also
+// reselects d's slave address via I2C_TENBIT and I2C_SLAVE, since another
+// Device sharing the Bus may have changed it. The
+// caller must call unlock when done, even on error.
+func (d *Device) lock() error {
+	d.fieldMu().Lock()
+
+	return d.selectSlave()
+}
+
+// unlock releases the lock acquired by lock.
+func (d *Device) unlock() {
+	d.fieldMu().Unlock()
+}
+
+// selectSlave reissues I2C_SLAVE (and I2C_TENBIT) for d's address. It is a
+// no-op for a Device that owns its file descriptor outright, since that
+// address was already selected when the Device was opened and nothing else
+// can change it.
+func (d *Device) selectSlave() error {
+	if d.shared == nil {
+		return nil
+	}
+
+	// I2C_TENBIT is set or cleared every time, since a 10-bit Device
+	// sharing the Bus may have left it set.
+	var tenBit uintptr
+	if d.addr.tenBit {
+		tenBit = 1
+	}
+
+	if err := ioctl(d.rc.Fd(), i2cTenbit, tenBit); err != nil {
+		return err
+	}
+
+	return ioctl(d.rc.Fd(), i2cSlave, uintptr(d.addr.raw))
+}
@@ -0,0 +1,122 @@
+package i2c
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// BusInfo describes one I²C adapter found under /dev, along with the
+// adapter name the kernel reports for it.
+type BusInfo struct {
+	Bus  int
+	Name string
+}
+
+// ListBuses scans /dev for i2c-N character devices and returns the buses it
+// finds, each paired with the adapter name read from
+// /sys/class/i2c-dev/i2c-N/name. This mirrors what i2cdetect(8) lists with
+// its -l flag.
+func ListBuses() ([]BusInfo, error) {
+	matches, err := filepath.Glob("/dev/i2c-*")
+	if err != nil {
+		return nil, err
+	}
+
+	buses := make([]BusInfo, 0, len(matches))
+
+	for _, m := range matches {
+		n, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(m), "i2c-"))
+		if err != nil {
+			continue
+		}
+
+		name, err := busName(n)
+		if err != nil {
+			return nil, err
+		}
+
+		buses = append(buses, BusInfo{Bus: n, Name: name})
+	}
+
+	sort.Slice(buses, func(i, j int) bool { return buses[i].Bus < buses[j].Bus })
+
+	return buses, nil
+}
+
+func busName(bus int) (string, error) {
+	f, err := os.Open(fmt.Sprintf("/sys/class/i2c-dev/i2c-%d/name", bus))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	if s.Scan() {
+		return s.Text(), nil
+	}
+
+	return "", s.Err()
+}
+
+// probeByteLo, probeByteHi, probeByteLo2, and probeByteHi2 bound the address
+// ranges i2cdetect(8) probes with a receive byte rather than a quick write,
+// since a bare write can corrupt some devices in those ranges (notably
+// certain EEPROMs).
+const (
+	probeByteLo  = 0x30
+	probeByteHi  = 0x37
+	probeByteLo2 = 0x50
+	probeByteHi2 = 0x5F
+)
+
+// Probe scans the 7-bit address range 0x03-0x77 on the bus, the same range
+// and technique i2cdetect(8) uses, and returns the addresses that
+// acknowledged.
+func (bi BusInfo) Probe() ([]uint8, error) {
+	var found []uint8
+
+	for addr := uint8(0x03); addr <= 0x77; addr++ {
+		ok, err := probeAddr(bi.Bus, addr)
+		if err != nil {
+			return found, err
+		}
+
+		if ok {
+			found = append(found, addr)
+		}
+	}
+
+	return found, nil
+}
+
+// probeAddr opens addr on bus just long enough to attempt a quick-write or
+// receive-byte SMBus transaction, reporting whether it ACKed.
+func probeAddr(bus int, addr uint8) (bool, error) {
+	d, err := New(bus, addr)
+	if err != nil {
+		if errors.Is(err, syscall.EBUSY) {
+			// A kernel driver already holds this address, so nothing
+			// answered our own probe.
+			return false, nil
+		}
+
+		return false, fmt.Errorf("opening bus %d addr 0x%0X: %v", bus, addr, err)
+	}
+	defer d.Close()
+
+	var probeErr error
+	if (addr >= probeByteLo && addr <= probeByteHi) || (addr >= probeByteLo2 && addr <= probeByteHi2) {
+		_, probeErr = d.SMBusReadByte()
+	} else {
+		probeErr = d.SMBusQuick(true)
+	}
+
+	return probeErr == nil, nil
+}
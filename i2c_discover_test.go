@@ -0,0 +1,42 @@
+package i2c
+
+import "testing"
+
+// TestProbeByteRanges checks the reserved address ranges Probe avoids
+// write-probing don't overlap and stay within the 7-bit address space.
+// ListBuses and Probe themselves aren't covered here: they read real
+// /dev and /sys/class/i2c-dev paths and issue real SMBus transactions, so
+// exercising them needs actual i2c-dev hardware (or a kernel i2c-stub bus),
+// not something a unit test can fake.
+func TestProbeByteRanges(t *testing.T) {
+	if probeByteLo > probeByteHi {
+		t.Fatalf("probeByteLo 0x%0X > probeByteHi 0x%0X", probeByteLo, probeByteHi)
+	}
+
+	if probeByteLo2 > probeByteHi2 {
+		t.Fatalf("probeByteLo2 0x%0X > probeByteHi2 0x%0X", probeByteLo2, probeByteHi2)
+	}
+
+	if probeByteHi >= probeByteLo2 {
+		t.Fatalf("reserved ranges overlap: probeByteHi 0x%0X >= probeByteLo2 0x%0X", probeByteHi, probeByteLo2)
+	}
+
+	if probeByteHi2 > 0x77 {
+		t.Fatalf("probeByteHi2 0x%0X exceeds the 7-bit address space probed by Probe", probeByteHi2)
+	}
+}
+
+// TestProbeAddrPropagatesNonEBUSYErrors checks that probeAddr only treats
+// EBUSY as "nothing answered"; every other failure to open the bus (here, a
+// bus number with no /dev/i2c-N at all) should come back as an error rather
+// than a silent false.
+func TestProbeAddrPropagatesNonEBUSYErrors(t *testing.T) {
+	ok, err := probeAddr(999999, 0x50)
+	if err == nil {
+		t.Fatal("probeAddr: expected an error for a nonexistent bus, got nil")
+	}
+
+	if ok {
+		t.Fatal("probeAddr: expected ok=false alongside the error")
+	}
+}
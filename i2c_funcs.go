@@ -0,0 +1,65 @@
+package i2c
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// Functionality is a bitmask of the capabilities an I²C adapter reports via
+// the I2C_FUNCS ioctl. Different adapters expose very different subsets of
+// these, so callers should check Functionality before relying on a
+// transaction type (e.g. Tx, or a given SMBus* method) being supported.
+type Functionality uint32
+
+// Functionality bits, mirroring the I2C_FUNC_* constants in <linux/i2c.h>.
+const (
+	FuncI2C                 Functionality = 0x00000001
+	FuncTenBitAddr          Functionality = 0x00000002
+	FuncProtocolMangling    Functionality = 0x00000004
+	FuncSMBusPEC            Functionality = 0x00000008
+	FuncNoStart             Functionality = 0x00000010
+	FuncSlave               Functionality = 0x00000020
+	FuncSMBusBlockProcCall  Functionality = 0x00008000
+	FuncSMBusQuick          Functionality = 0x00010000
+	FuncSMBusReadByte       Functionality = 0x00020000
+	FuncSMBusWriteByte      Functionality = 0x00040000
+	FuncSMBusReadByteData   Functionality = 0x00080000
+	FuncSMBusWriteByteData  Functionality = 0x00100000
+	FuncSMBusReadWordData   Functionality = 0x00200000
+	FuncSMBusWriteWordData  Functionality = 0x00400000
+	FuncSMBusProcCall       Functionality = 0x00800000
+	FuncSMBusReadBlockData  Functionality = 0x01000000
+	FuncSMBusWriteBlockData Functionality = 0x02000000
+	FuncSMBusReadI2CBlock   Functionality = 0x04000000
+	FuncSMBusWriteI2CBlock  Functionality = 0x08000000
+	FuncSMBusHostNotify     Functionality = 0x10000000
+)
+
+// Has reports whether every bit set in want is also set in f.
+func (f Functionality) Has(want Functionality) bool {
+	return f&want == want
+}
+
+// Funcs issues the I2C_FUNCS ioctl and returns the bitmask of transaction
+// types the underlying adapter supports.
+func (d *Device) Funcs() (Functionality, error) {
+	if err := d.lock(); err != nil {
+		d.unlock()
+		return 0, err
+	}
+	defer d.unlock()
+
+	var f Functionality
+
+	// The Pointer->uintptr conversion is done directly in this Syscall6 call,
+	// rather than passed through the ioctl helper, per the unsafe package's
+	// documented syscall convention: that's what keeps f alive until the
+	// call completes.
+	_, _, errno := syscall.Syscall6(syscall.SYS_IOCTL, d.rc.Fd(), i2cFuncs, uintptr(unsafe.Pointer(&f)), 0, 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+
+	d.debugf("Funcs 0x%08X", uint32(f))
+	return f, nil
+}
@@ -0,0 +1,23 @@
+package i2c
+
+import "testing"
+
+func TestFunctionalityHas(t *testing.T) {
+	f := FuncI2C | FuncSMBusReadByte
+
+	if !f.Has(FuncI2C) {
+		t.Fatal("Has(FuncI2C) = false, want true")
+	}
+
+	if !f.Has(FuncI2C | FuncSMBusReadByte) {
+		t.Fatal("Has(FuncI2C|FuncSMBusReadByte) = false, want true")
+	}
+
+	if f.Has(FuncSMBusWriteByte) {
+		t.Fatal("Has(FuncSMBusWriteByte) = true, want false")
+	}
+
+	if f.Has(FuncI2C | FuncSMBusWriteByte) {
+		t.Fatal("Has should require every requested bit, not just one")
+	}
+}
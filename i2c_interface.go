@@ -0,0 +1,48 @@
+package i2c
+
+// I2C is the method set of Device. It exists so that code built on top of
+// this package can depend on an interface instead of *Device, letting tests
+// substitute a fake (see the mocki2c subpackage) instead of needing real
+// Linux i2c-dev hardware.
+type I2C interface {
+	SetDebugf(debugf func(format string, args ...interface{}))
+	Bus() int
+	Addr() Address
+	Close() error
+
+	Write(p []byte) (int, error)
+	WriteByte(b byte) (int, error)
+	WriteReg(p []byte, reg byte) (int, error)
+	Read(p []byte) (int, error)
+	ReadReg(p []byte, reg byte) (int, error)
+
+	ReadRegU8(reg byte) (byte, error)
+	WriteRegU8(reg byte, value byte) error
+	ReadRegU16BE(reg byte) (uint16, error)
+	ReadRegU16LE(reg byte) (uint16, error)
+	ReadRegS16BE(reg byte) (int16, error)
+	ReadRegS16LE(reg byte) (int16, error)
+	WriteRegU16BE(reg byte, value uint16) error
+	WriteRegU16LE(reg byte, value uint16) error
+	WriteRegS16BE(reg byte, value int16) error
+	WriteRegS16LE(reg byte, value int16) error
+
+	Tx(msgs []Message) error
+	Funcs() (Functionality, error)
+
+	SetPEC(enable bool) error
+	SMBusQuick(write bool) error
+	SMBusReadByte() (byte, error)
+	SMBusWriteByte(value byte) error
+	SMBusReadWordData(reg byte) (uint16, error)
+	SMBusWriteWordData(reg byte, value uint16) error
+	SMBusReadBlockData(reg byte) ([]byte, error)
+	SMBusWriteBlockData(reg byte, p []byte) error
+	SMBusReadI2CBlockData(reg byte, length uint8) ([]byte, error)
+	SMBusWriteI2CBlockData(reg byte, p []byte) error
+	SMBusProcessCall(reg byte, value uint16) (uint16, error)
+	SMBusBlockProcessCall(reg byte, p []byte) ([]byte, error)
+}
+
+// assert that Device satisfies I2C.
+var _ I2C = (*Device)(nil)
@@ -0,0 +1,19 @@
+package i2c
+
+// ioctl request numbers for /dev/i2c-N character devices. These come from
+// <linux/i2c-dev.h> and <linux/i2c.h> and are part of the stable Linux UAPI,
+// so they're hardcoded here as plain Go constants rather than pulled in via
+// cgo. That keeps this package - including the pure-Go I2C interface and the
+// mocki2c fake built on it - buildable with CGO_ENABLED=0 and on other
+// Unix-like platforms such as macOS; only the ioctl calls themselves require
+// real Linux i2c-dev hardware to succeed. It does not build on Windows, which
+// has no syscall.SYS_IOCTL.
+const (
+	i2cSlave      = 0x0703
+	i2cTenbit     = 0x0704
+	i2cFuncs      = 0x0705
+	i2cSlaveForce = 0x0706
+	i2cRdwr       = 0x0707
+	i2cPec        = 0x0708
+	i2cSmbus      = 0x0720
+)
@@ -0,0 +1,28 @@
+package i2c
+
+// Options customizes how NewWithOptions opens a Device.
+type Options struct {
+	// Force claims the address with I2C_SLAVE_FORCE instead of I2C_SLAVE,
+	// so the open succeeds even if a kernel driver has already bound the
+	// address (e.g. rtc-ds1307 claiming a real-time clock's address on
+	// many Raspberry Pi images). Without this, opening the device fails
+	// with EBUSY and the caller has to unload the driver first.
+	Force bool
+}
+
+// NewWithOptions is like New, but lets the caller customize how the device
+// at the specified 7-bit address is opened; see Options.
+func NewWithOptions(bus int, addr uint8, opts Options) (*Device, error) {
+	return newDevice(bus, Addr7(addr), opts)
+}
+
+// slaveIoctl picks the ioctl request newDevice uses to claim a slave
+// address: I2C_SLAVE_FORCE if opts asks to claim a busy address, or the
+// ordinary I2C_SLAVE otherwise.
+func slaveIoctl(opts Options) uintptr {
+	if opts.Force {
+		return uintptr(i2cSlaveForce)
+	}
+
+	return uintptr(i2cSlave)
+}
@@ -0,0 +1,13 @@
+package i2c
+
+import "testing"
+
+func TestSlaveIoctl(t *testing.T) {
+	if got := slaveIoctl(Options{}); got != uintptr(i2cSlave) {
+		t.Fatalf("slaveIoctl(Options{}) = 0x%X, want I2C_SLAVE (0x%X)", got, i2cSlave)
+	}
+
+	if got := slaveIoctl(Options{Force: true}); got != uintptr(i2cSlaveForce) {
+		t.Fatalf("slaveIoctl(Options{Force: true}) = 0x%X, want I2C_SLAVE_FORCE (0x%X)", got, i2cSlaveForce)
+	}
+}
@@ -0,0 +1,122 @@
+package i2c
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// maxRdwrMsgs is I2C_RDRW_IOCTL_MAX_MSGS: the largest number of Messages the
+// kernel will accept in a single Tx call.
+const maxRdwrMsgs = 42
+
+// MessageFlag is a bitmask of per-message behaviors for a combined I2C_RDWR
+// transaction. These map directly to the i2c_msg flags defined in
+// <linux/i2c.h>.
+type MessageFlag uint16
+
+// Message flags understood by Tx. Read, TenBit, and NoStart mirror
+// I2C_M_RD, I2C_M_TEN, and I2C_M_NOSTART respectively.
+const (
+	// Read marks the message as a read from the device. Without it, the
+	// message is a write.
+	Read MessageFlag = 0x0001
+	// TenBit marks Message.Addr as a 10-bit address rather than 7-bit.
+	TenBit MessageFlag = 0x0010
+	// NoStart skips the repeated START that would otherwise precede this
+	// message, chaining it onto the previous message's data phase.
+	NoStart MessageFlag = 0x4000
+)
+
+// Message is a single segment of a combined I2C_RDWR transaction: a slave
+// address, a set of flags describing how to address and frame it, and the
+// buffer to write from or read into.
+type Message struct {
+	Addr  uint16
+	Flags MessageFlag
+	Buf   []byte
+}
+
+// NewMessage builds a Message addressed to addr, setting the TenBit flag
+// whenever addr is a 10-bit Address so the kernel frames it with I2C_M_TEN
+// instead of as a plain 7-bit address, and OR'ing in any additional flags
+// the caller passes (e.g. Read, NoStart).
+func NewMessage(addr Address, flags MessageFlag, buf []byte) Message {
+	if addr.tenBit {
+		flags |= TenBit
+	}
+
+	return Message{Addr: addr.raw, Flags: flags, Buf: buf}
+}
+
+// i2cMsg mirrors struct i2c_msg from <linux/i2c.h>.
+type i2cMsg struct {
+	addr  uint16
+	flags uint16
+	len   uint16
+	buf   *byte
+}
+
+// i2cRdwrIoctlData mirrors struct i2c_rdwr_ioctl_data from
+// <linux/i2c-dev.h>.
+type i2cRdwrIoctlData struct {
+	msgs  *i2cMsg
+	nmsgs uint32
+}
+
+// Tx issues a single I2C_RDWR ioctl carrying every Message in msgs, letting
+// the kernel chain them under one START and a single trailing STOP. This is
+// what lets a caller do a repeated-START write-then-read against devices
+// that reject the STOP that ReadReg emits between the register write and
+// the read, and lets one Device address more than one slave in a single
+// transaction.
+func (d *Device) Tx(msgs []Message) error {
+	n := len(msgs)
+	if n == 0 {
+		return errors.New("minimum message count 1")
+	}
+
+	if n > maxRdwrMsgs {
+		return fmt.Errorf("maximum message count %d, was %d", maxRdwrMsgs, n)
+	}
+
+	raw := make([]i2cMsg, n)
+
+	for i, m := range msgs {
+		if len(m.Buf) == 0 {
+			return errors.New("minimum message length 1")
+		}
+
+		raw[i] = i2cMsg{
+			addr:  m.Addr,
+			flags: uint16(m.Flags),
+			len:   uint16(len(m.Buf)),
+			buf:   &m.Buf[0],
+		}
+	}
+
+	data := i2cRdwrIoctlData{
+		msgs:  &raw[0],
+		nmsgs: uint32(n),
+	}
+
+	if err := d.lock(); err != nil {
+		d.unlock()
+		return err
+	}
+	defer d.unlock()
+
+	d.debugf("Tx %d messages", n)
+
+	// The Pointer->uintptr conversion is done directly in this Syscall6 call,
+	// rather than passed through the ioctl helper, per the unsafe package's
+	// documented syscall convention: that's what keeps data, and the raw
+	// messages and buffers it points into, alive until the call completes.
+	_, _, errno := syscall.Syscall6(syscall.SYS_IOCTL, d.rc.Fd(), i2cRdwr, uintptr(unsafe.Pointer(&data)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
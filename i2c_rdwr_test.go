@@ -0,0 +1,54 @@
+package i2c
+
+import "testing"
+
+func TestTxValidation(t *testing.T) {
+	d := &Device{}
+
+	if err := d.Tx(nil); err == nil {
+		t.Fatal("Tx(nil): expected error for zero messages, got nil")
+	}
+
+	msgs := make([]Message, maxRdwrMsgs+1)
+	for i := range msgs {
+		msgs[i] = Message{Buf: []byte{0x00}}
+	}
+
+	if err := d.Tx(msgs); err == nil {
+		t.Fatalf("Tx: expected error for %d messages (max %d), got nil", len(msgs), maxRdwrMsgs)
+	}
+
+	if err := d.Tx([]Message{{Buf: nil}}); err == nil {
+		t.Fatal("Tx: expected error for an empty message buffer, got nil")
+	}
+}
+
+func TestNewMessageTenBit(t *testing.T) {
+	buf := []byte{0x01}
+
+	m := NewMessage(TenBitAddr(0x3FF), Read, buf)
+
+	if m.Addr != 0x3FF {
+		t.Fatalf("Addr = 0x%0X, want 0x3FF", m.Addr)
+	}
+
+	if m.Flags&TenBit == 0 {
+		t.Fatal("NewMessage with a 10-bit Address must set the TenBit flag")
+	}
+
+	if m.Flags&Read == 0 {
+		t.Fatal("NewMessage must preserve flags passed in alongside TenBit")
+	}
+}
+
+func TestNewMessageSevenBit(t *testing.T) {
+	m := NewMessage(Addr7(0x50), Read, []byte{0x01})
+
+	if m.Addr != 0x50 {
+		t.Fatalf("Addr = 0x%0X, want 0x50", m.Addr)
+	}
+
+	if m.Flags&TenBit != 0 {
+		t.Fatal("NewMessage with a 7-bit Address must not set the TenBit flag")
+	}
+}
@@ -0,0 +1,358 @@
+package i2c
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// smbusBlockMax is I2C_SMBUS_BLOCK_MAX: the largest block of data the SMBus
+// protocol allows in a single block/process-call transaction.
+const smbusBlockMax = 32
+
+// i2c_smbus_ioctl_data.read_write markers, from <linux/i2c.h>.
+const (
+	smbusRead  = 1
+	smbusWrite = 0
+)
+
+// i2c_smbus_ioctl_data.size values, identifying the SMBus transaction type,
+// from <linux/i2c.h>.
+const (
+	smbusQuick         = 0
+	smbusByte          = 1
+	smbusByteData      = 2
+	smbusWordData      = 3
+	smbusProcCall      = 4
+	smbusBlockData     = 5
+	smbusBlockProcCall = 7
+	smbusI2CBlockData  = 8
+)
+
+// i2cSmbusData mirrors union i2c_smbus_data from <linux/i2c.h>: a byte, a
+// word, or a length-prefixed block of up to smbusBlockMax bytes (plus one
+// spare byte for userspace compatibility).
+type i2cSmbusData [smbusBlockMax + 2]byte
+
+func (d *i2cSmbusData) getByte() byte { return d[0] }
+
+func (d *i2cSmbusData) setByte(b byte) { d[0] = b }
+
+func (d *i2cSmbusData) getWord() uint16 { return uint16(d[0]) | uint16(d[1])<<8 }
+
+func (d *i2cSmbusData) setWord(w uint16) {
+	d[0] = byte(w)
+	d[1] = byte(w >> 8)
+}
+
+func (d *i2cSmbusData) getBlock() []byte {
+	n := int(d[0])
+	if n > smbusBlockMax {
+		// A length byte this large can only come from a misbehaving
+		// adapter driver or device, not a real SMBus block transfer; clamp
+		// it instead of slicing out of bounds.
+		n = smbusBlockMax
+	}
+
+	buf := make([]byte, n)
+	copy(buf, d[1:1+n])
+	return buf
+}
+
+func (d *i2cSmbusData) setBlock(p []byte) error {
+	if len(p) > smbusBlockMax {
+		return fmt.Errorf("maximum block length %d, was %d", smbusBlockMax, len(p))
+	}
+
+	d[0] = byte(len(p))
+	copy(d[1:], p)
+	return nil
+}
+
+// i2cSmbusIoctlData mirrors struct i2c_smbus_ioctl_data from
+// <linux/i2c-dev.h>.
+type i2cSmbusIoctlData struct {
+	readWrite uint8
+	command   uint8
+	size      uint32
+	data      *i2cSmbusData
+}
+
+// smbusAccess issues the I2C_SMBUS ioctl, the primitive every SMBus method
+// below is built on.
+func (d *Device) smbusAccess(readWrite uint8, command byte, size uint32, data *i2cSmbusData) error {
+	args := i2cSmbusIoctlData{
+		readWrite: readWrite,
+		command:   command,
+		size:      size,
+		data:      data,
+	}
+
+	if err := d.lock(); err != nil {
+		d.unlock()
+		return err
+	}
+	defer d.unlock()
+
+	// The Pointer->uintptr conversion is done directly in this Syscall6 call,
+	// rather than passed through the ioctl helper, per the unsafe package's
+	// documented syscall convention: that's what keeps args, and the data it
+	// points into, alive until the call completes.
+	_, _, errno := syscall.Syscall6(syscall.SYS_IOCTL, d.rc.Fd(), i2cSmbus, uintptr(unsafe.Pointer(&args)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+// requireFunc checks that the adapter reports support for want via
+// I2C_FUNCS, returning a clear error instead of letting an unsupported
+// SMBus transaction fail with an opaque EIO from the kernel.
+func (d *Device) requireFunc(want Functionality, name string) error {
+	funcs, err := d.Funcs()
+	if err != nil {
+		return fmt.Errorf("checking adapter support for %s: %v", name, err)
+	}
+
+	if !funcs.Has(want) {
+		return fmt.Errorf("adapter does not support %s", name)
+	}
+
+	return nil
+}
+
+// SetPEC enables or disables SMBus Packet Error Checking for subsequent
+// SMBus transactions on this Device, via the I2C_PEC ioctl.
+func (d *Device) SetPEC(enable bool) error {
+	if err := d.requireFunc(FuncSMBusPEC, "SMBus PEC"); err != nil {
+		return err
+	}
+
+	var v uintptr
+	if enable {
+		v = 1
+	}
+
+	if err := d.lock(); err != nil {
+		d.unlock()
+		return err
+	}
+	defer d.unlock()
+
+	return ioctl(d.rc.Fd(), i2cPec, v)
+}
+
+// SMBusQuick sends an SMBus Quick Command: the read/write bit alone, with no
+// command or data byte. It's commonly used to probe whether a device ACKs a
+// given address.
+func (d *Device) SMBusQuick(write bool) error {
+	if err := d.requireFunc(FuncSMBusQuick, "SMBus Quick Command"); err != nil {
+		return err
+	}
+
+	rw := uint8(smbusRead)
+	if write {
+		rw = smbusWrite
+	}
+
+	return d.smbusAccess(rw, 0, smbusQuick, nil)
+}
+
+// SMBusReadByte performs an SMBus Read Byte transaction, reading a single
+// byte from the device without addressing a register.
+func (d *Device) SMBusReadByte() (byte, error) {
+	if err := d.requireFunc(FuncSMBusReadByte, "SMBus Read Byte"); err != nil {
+		return 0, err
+	}
+
+	var data i2cSmbusData
+
+	if err := d.smbusAccess(smbusRead, 0, smbusByte, &data); err != nil {
+		return 0, err
+	}
+
+	b := data.getByte()
+	d.debugf("SMBus read byte %d", b)
+	return b, nil
+}
+
+// SMBusWriteByte performs an SMBus Write Byte transaction, writing value as
+// the command byte with no following data.
+func (d *Device) SMBusWriteByte(value byte) error {
+	if err := d.requireFunc(FuncSMBusWriteByte, "SMBus Write Byte"); err != nil {
+		return err
+	}
+
+	if err := d.smbusAccess(smbusWrite, value, smbusByte, nil); err != nil {
+		return err
+	}
+
+	d.debugf("SMBus write byte %d", value)
+	return nil
+}
+
+// SMBusReadWordData performs an SMBus Read Word Data transaction, reading a
+// 16-bit little-endian word from the register specified by reg.
+func (d *Device) SMBusReadWordData(reg byte) (uint16, error) {
+	if err := d.requireFunc(FuncSMBusReadWordData, "SMBus Read Word Data"); err != nil {
+		return 0, err
+	}
+
+	var data i2cSmbusData
+
+	if err := d.smbusAccess(smbusRead, reg, smbusWordData, &data); err != nil {
+		return 0, err
+	}
+
+	w := data.getWord()
+	d.debugf("SMBus read word %d from reg 0x%0X", w, reg)
+	return w, nil
+}
+
+// SMBusWriteWordData performs an SMBus Write Word Data transaction, writing
+// a 16-bit little-endian word to the register specified by reg.
+func (d *Device) SMBusWriteWordData(reg byte, value uint16) error {
+	if err := d.requireFunc(FuncSMBusWriteWordData, "SMBus Write Word Data"); err != nil {
+		return err
+	}
+
+	var data i2cSmbusData
+	data.setWord(value)
+
+	if err := d.smbusAccess(smbusWrite, reg, smbusWordData, &data); err != nil {
+		return err
+	}
+
+	d.debugf("SMBus write word %d to reg 0x%0X", value, reg)
+	return nil
+}
+
+// SMBusReadBlockData performs an SMBus Read Block Data transaction, reading
+// a variable-length, length-prefixed block from the register specified by
+// reg. The device itself supplies the block length (up to 32 bytes).
+func (d *Device) SMBusReadBlockData(reg byte) ([]byte, error) {
+	if err := d.requireFunc(FuncSMBusReadBlockData, "SMBus Read Block Data"); err != nil {
+		return nil, err
+	}
+
+	var data i2cSmbusData
+
+	if err := d.smbusAccess(smbusRead, reg, smbusBlockData, &data); err != nil {
+		return nil, err
+	}
+
+	p := data.getBlock()
+	d.debugf("SMBus read block %d bytes from reg 0x%0X", len(p), reg)
+	return p, nil
+}
+
+// SMBusWriteBlockData performs an SMBus Write Block Data transaction,
+// writing a variable-length, length-prefixed block (up to 32 bytes) to the
+// register specified by reg.
+func (d *Device) SMBusWriteBlockData(reg byte, p []byte) error {
+	if err := d.requireFunc(FuncSMBusWriteBlockData, "SMBus Write Block Data"); err != nil {
+		return err
+	}
+
+	var data i2cSmbusData
+	if err := data.setBlock(p); err != nil {
+		return err
+	}
+
+	if err := d.smbusAccess(smbusWrite, reg, smbusBlockData, &data); err != nil {
+		return err
+	}
+
+	d.debugf("SMBus write block %d bytes to reg 0x%0X", len(p), reg)
+	return nil
+}
+
+// SMBusReadI2CBlockData performs an I2C Block Read (the SMBus variant that
+// lets the caller pick the block length instead of relying on the device to
+// prefix it), reading length bytes (up to 32) from the register specified
+// by reg.
+func (d *Device) SMBusReadI2CBlockData(reg byte, length uint8) ([]byte, error) {
+	if length == 0 || length > smbusBlockMax {
+		return nil, fmt.Errorf("maximum block length %d, was %d", smbusBlockMax, length)
+	}
+
+	if err := d.requireFunc(FuncSMBusReadI2CBlock, "SMBus Read I2C Block Data"); err != nil {
+		return nil, err
+	}
+
+	var data i2cSmbusData
+	data[0] = length
+
+	if err := d.smbusAccess(smbusRead, reg, smbusI2CBlockData, &data); err != nil {
+		return nil, err
+	}
+
+	p := data.getBlock()
+	d.debugf("SMBus read I2C block %d bytes from reg 0x%0X", len(p), reg)
+	return p, nil
+}
+
+// SMBusWriteI2CBlockData performs an I2C Block Write, writing p (up to 32
+// bytes) to the register specified by reg without a device-supplied length
+// prefix.
+func (d *Device) SMBusWriteI2CBlockData(reg byte, p []byte) error {
+	if err := d.requireFunc(FuncSMBusWriteI2CBlock, "SMBus Write I2C Block Data"); err != nil {
+		return err
+	}
+
+	var data i2cSmbusData
+	if err := data.setBlock(p); err != nil {
+		return err
+	}
+
+	if err := d.smbusAccess(smbusWrite, reg, smbusI2CBlockData, &data); err != nil {
+		return err
+	}
+
+	d.debugf("SMBus write I2C block %d bytes to reg 0x%0X", len(p), reg)
+	return nil
+}
+
+// SMBusProcessCall performs an SMBus Process Call: it writes a 16-bit word
+// to the register specified by reg and, in the same transaction, reads back
+// a 16-bit word the device computed in response.
+func (d *Device) SMBusProcessCall(reg byte, value uint16) (uint16, error) {
+	if err := d.requireFunc(FuncSMBusProcCall, "SMBus Process Call"); err != nil {
+		return 0, err
+	}
+
+	var data i2cSmbusData
+	data.setWord(value)
+
+	if err := d.smbusAccess(smbusWrite, reg, smbusProcCall, &data); err != nil {
+		return 0, err
+	}
+
+	w := data.getWord()
+	d.debugf("SMBus process call reg 0x%0X: wrote %d, read %d", reg, value, w)
+	return w, nil
+}
+
+// SMBusBlockProcessCall performs an SMBus Block Process Call: it writes the
+// length-prefixed block p to the register specified by reg and, in the same
+// transaction, reads back a length-prefixed block the device computed in
+// response.
+func (d *Device) SMBusBlockProcessCall(reg byte, p []byte) ([]byte, error) {
+	if err := d.requireFunc(FuncSMBusBlockProcCall, "SMBus Block Process Call"); err != nil {
+		return nil, err
+	}
+
+	var data i2cSmbusData
+	if err := data.setBlock(p); err != nil {
+		return nil, err
+	}
+
+	if err := d.smbusAccess(smbusWrite, reg, smbusBlockProcCall, &data); err != nil {
+		return nil, err
+	}
+
+	out := data.getBlock()
+	d.debugf("SMBus block process call reg 0x%0X: wrote %d bytes, read %d bytes", reg, len(p), len(out))
+	return out, nil
+}
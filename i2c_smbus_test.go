@@ -0,0 +1,61 @@
+package i2c
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestI2CSmbusDataBlock(t *testing.T) {
+	var data i2cSmbusData
+
+	p := []byte{0x01, 0x02, 0x03}
+	if err := data.setBlock(p); err != nil {
+		t.Fatalf("setBlock: %v", err)
+	}
+
+	if got := data.getBlock(); !bytes.Equal(got, p) {
+		t.Fatalf("getBlock() = %v, want %v", got, p)
+	}
+}
+
+func TestI2CSmbusDataBlockTooLong(t *testing.T) {
+	var data i2cSmbusData
+
+	p := make([]byte, smbusBlockMax+1)
+
+	if err := data.setBlock(p); err == nil {
+		t.Fatalf("setBlock: expected error for %d bytes (max %d), got nil", len(p), smbusBlockMax)
+	}
+}
+
+func TestI2CSmbusDataBlockOversizedLength(t *testing.T) {
+	var data i2cSmbusData
+	data[0] = 200
+
+	got := data.getBlock()
+	if len(got) != smbusBlockMax {
+		t.Fatalf("getBlock() length = %d for a corrupt length byte of 200, want clamped to %d", len(got), smbusBlockMax)
+	}
+}
+
+func TestI2CSmbusDataWord(t *testing.T) {
+	var data i2cSmbusData
+
+	data.setWord(0xABCD)
+
+	if got := data.getWord(); got != 0xABCD {
+		t.Fatalf("getWord() = 0x%0X, want 0xABCD", got)
+	}
+}
+
+func TestSMBusReadI2CBlockDataLength(t *testing.T) {
+	d := &Device{}
+
+	if _, err := d.SMBusReadI2CBlockData(0x00, 0); err == nil {
+		t.Fatal("SMBusReadI2CBlockData: expected error for zero length, got nil")
+	}
+
+	if _, err := d.SMBusReadI2CBlockData(0x00, smbusBlockMax+1); err == nil {
+		t.Fatalf("SMBusReadI2CBlockData: expected error for length %d (max %d), got nil", smbusBlockMax+1, smbusBlockMax)
+	}
+}
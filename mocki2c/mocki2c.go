@@ -0,0 +1,436 @@
+// Package mocki2c provides a programmable, in-memory fake that satisfies
+// i2c.I2C, for testing driver code written on top of go-i2c without real
+// Linux i2c-dev hardware.
+package mocki2c
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	i2c "github.com/theckman/go-i2c"
+)
+
+// Handler responds to an access to a single register: it receives the
+// bytes written (nil for a pure read) and returns the bytes to hand back
+// to the caller.
+type Handler func(write []byte) (read []byte)
+
+// maskedHandler is a Handler gated by a command bitmask rather than an
+// exact register match, e.g. for devices that pack flags into the high
+// bits of a command byte alongside a register index in the low bits.
+type maskedHandler struct {
+	match, mask byte
+	fn          Handler
+}
+
+// Device is a programmable fake of *i2c.Device. The zero value is not
+// usable; construct one with New.
+type Device struct {
+	mu sync.Mutex
+
+	bus     int
+	addr    i2c.Address
+	debugf  func(string, ...interface{})
+	funcs   i2c.Functionality
+	lastReg byte
+
+	handlers map[byte]Handler
+	masked   []maskedHandler
+	calls    map[byte]int
+}
+
+// New returns a Device fake bound to the given bus and address, with no
+// registered handlers. Every access will fail until a handler is
+// registered for the register it targets, via RegisterHandler or
+// RegisterMaskedHandler.
+func New(bus int, addr uint8) *Device {
+	return &Device{
+		bus:      bus,
+		addr:     i2c.Addr7(addr),
+		debugf:   i2c.NOOPDebugf,
+		handlers: make(map[byte]Handler),
+		calls:    make(map[byte]int),
+	}
+}
+
+// RegisterHandler installs fn as the handler for register reg: every read
+// or write addressed to reg is routed to fn instead of failing.
+func (d *Device) RegisterHandler(reg byte, fn Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlers[reg] = fn
+}
+
+// RegisterMaskedHandler installs fn as the handler for every command byte c
+// such that c&mask == match&mask. Exact handlers registered with
+// RegisterHandler are tried first; masked handlers are then tried in
+// registration order.
+func (d *Device) RegisterMaskedHandler(match, mask byte, fn Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.masked = append(d.masked, maskedHandler{match: match, mask: mask, fn: fn})
+}
+
+// CallCount returns the number of reads and writes reg has seen, whether
+// they were served by an exact or a masked handler.
+func (d *Device) CallCount(reg byte) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.calls[reg]
+}
+
+// SetFuncs configures the Functionality bitmask Funcs reports.
+func (d *Device) SetFuncs(f i2c.Functionality) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.funcs = f
+}
+
+func (d *Device) handlerFor(reg byte) (Handler, bool) {
+	if fn, ok := d.handlers[reg]; ok {
+		return fn, true
+	}
+
+	for _, m := range d.masked {
+		if reg&m.mask == m.match&m.mask {
+			return m.fn, true
+		}
+	}
+
+	return nil, false
+}
+
+func (d *Device) dispatch(reg byte, write []byte) ([]byte, error) {
+	d.mu.Lock()
+	fn, ok := d.handlerFor(reg)
+	d.calls[reg]++
+	d.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("mocki2c: no handler registered for reg 0x%0X", reg)
+	}
+
+	return fn(write), nil
+}
+
+// SetDebugf sets a formatted debug function, mirroring Device.SetDebugf.
+func (d *Device) SetDebugf(debugf func(format string, args ...interface{})) {
+	d.debugf = debugf
+}
+
+// Bus returns the bus number the Device was created with.
+func (d *Device) Bus() int {
+	return d.bus
+}
+
+// Addr returns the address the Device was created with.
+func (d *Device) Addr() i2c.Address {
+	return d.addr
+}
+
+// Close is a no-op; the fake owns no file descriptor.
+func (d *Device) Close() error {
+	return nil
+}
+
+// Write treats p[0] as a register address and the rest of p as the bytes
+// written to it, the same framing Device.Write uses for register-style
+// writes. It also remembers the register for a following Read.
+func (d *Device) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, errors.New("minimum message length 1")
+	}
+
+	reg := p[0]
+
+	d.mu.Lock()
+	d.lastReg = reg
+	d.mu.Unlock()
+
+	if _, err := d.dispatch(reg, p[1:]); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// WriteByte writes a single byte, per Device.WriteByte.
+func (d *Device) WriteByte(b byte) (int, error) {
+	return d.Write([]byte{b})
+}
+
+// WriteReg writes p to register reg, per Device.WriteReg.
+func (d *Device) WriteReg(p []byte, reg byte) (int, error) {
+	buf := make([]byte, 0, len(p)+1)
+	buf = append(buf, reg)
+	buf = append(buf, p...)
+
+	return d.Write(buf)
+}
+
+// Read serves p from the handler for the register last addressed by Write,
+// WriteByte, WriteReg, or ReadReg, mirroring how Device.Read depends on a
+// preceding register write.
+func (d *Device) Read(p []byte) (int, error) {
+	d.mu.Lock()
+	reg := d.lastReg
+	d.mu.Unlock()
+
+	resp, err := d.dispatch(reg, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return copy(p, resp), nil
+}
+
+// ReadReg serves p from the handler for reg, per Device.ReadReg.
+func (d *Device) ReadReg(p []byte, reg byte) (int, error) {
+	d.mu.Lock()
+	d.lastReg = reg
+	d.mu.Unlock()
+
+	resp, err := d.dispatch(reg, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return copy(p, resp), nil
+}
+
+// ReadRegU8 reads a byte from reg, per Device.ReadRegU8.
+func (d *Device) ReadRegU8(reg byte) (byte, error) {
+	buf := make([]byte, 1)
+
+	if _, err := d.ReadReg(buf, reg); err != nil {
+		return 0, err
+	}
+
+	return buf[0], nil
+}
+
+// WriteRegU8 writes a byte to reg, per Device.WriteRegU8.
+func (d *Device) WriteRegU8(reg byte, value byte) error {
+	_, err := d.Write([]byte{reg, value})
+	return err
+}
+
+// ReadRegU16BE reads a big endian word from reg, per Device.ReadRegU16BE.
+func (d *Device) ReadRegU16BE(reg byte) (uint16, error) {
+	buf := make([]byte, 2)
+
+	if _, err := d.ReadReg(buf, reg); err != nil {
+		return 0, err
+	}
+
+	return uint16(buf[0])<<8 + uint16(buf[1]), nil
+}
+
+// ReadRegU16LE reads a little endian word from reg, per Device.ReadRegU16LE.
+func (d *Device) ReadRegU16LE(reg byte) (uint16, error) {
+	w, err := d.ReadRegU16BE(reg)
+	if err != nil {
+		return 0, err
+	}
+
+	return (w&0xFF)<<8 + w>>8, nil
+}
+
+// ReadRegS16BE reads a signed big endian word from reg, per
+// Device.ReadRegS16BE.
+func (d *Device) ReadRegS16BE(reg byte) (int16, error) {
+	buf := make([]byte, 2)
+
+	if _, err := d.ReadReg(buf, reg); err != nil {
+		return 0, err
+	}
+
+	return int16(buf[0])<<8 + int16(buf[1]), nil
+}
+
+// ReadRegS16LE reads a signed little endian word from reg, per
+// Device.ReadRegS16LE.
+func (d *Device) ReadRegS16LE(reg byte) (int16, error) {
+	w, err := d.ReadRegS16BE(reg)
+	if err != nil {
+		return 0, err
+	}
+
+	return (w&0xFF)<<8 + int16(uint16(w)>>8), nil
+}
+
+// WriteRegU16BE writes a big endian word to reg, per Device.WriteRegU16BE.
+func (d *Device) WriteRegU16BE(reg byte, value uint16) error {
+	_, err := d.Write([]byte{reg, byte(value >> 8), byte(value)})
+	return err
+}
+
+// WriteRegU16LE writes a little endian word to reg, per Device.WriteRegU16LE.
+func (d *Device) WriteRegU16LE(reg byte, value uint16) error {
+	w := (value&0xFF00)>>8 + value<<8
+	return d.WriteRegU16BE(reg, w)
+}
+
+// WriteRegS16BE writes a signed big endian word to reg, per
+// Device.WriteRegS16BE.
+func (d *Device) WriteRegS16BE(reg byte, value int16) error {
+	_, err := d.Write([]byte{reg, byte(uint16(value) >> 8), byte(value)})
+	return err
+}
+
+// WriteRegS16LE writes a signed little endian word to reg, per
+// Device.WriteRegS16LE.
+func (d *Device) WriteRegS16LE(reg byte, value int16) error {
+	w := int16((uint16(value)&0xFF00)>>8) + value<<8
+	return d.WriteRegS16BE(reg, w)
+}
+
+// Tx replays a combined-transaction Message slice against registered
+// handlers: a write Message's first buffer byte selects the register
+// (remembered for any read Message that follows), and a read Message is
+// served from that register's handler.
+func (d *Device) Tx(msgs []i2c.Message) error {
+	for _, m := range msgs {
+		if m.Flags&i2c.Read != 0 {
+			d.mu.Lock()
+			reg := d.lastReg
+			d.mu.Unlock()
+
+			resp, err := d.dispatch(reg, nil)
+			if err != nil {
+				return err
+			}
+
+			copy(m.Buf, resp)
+			continue
+		}
+
+		if len(m.Buf) == 0 {
+			return errors.New("mocki2c: minimum message length 1")
+		}
+
+		reg := m.Buf[0]
+
+		d.mu.Lock()
+		d.lastReg = reg
+		d.mu.Unlock()
+
+		if _, err := d.dispatch(reg, m.Buf[1:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Funcs returns the Functionality bitmask configured with SetFuncs.
+func (d *Device) Funcs() (i2c.Functionality, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.funcs, nil
+}
+
+// SetPEC is a no-op; the fake has no PEC framing to toggle.
+func (d *Device) SetPEC(enable bool) error {
+	return nil
+}
+
+// SMBusQuick is a no-op that always succeeds; there is no register to
+// route a quick command through.
+func (d *Device) SMBusQuick(write bool) error {
+	return nil
+}
+
+// SMBusReadByte always returns 0; there is no register to route a
+// register-less SMBus byte read through.
+func (d *Device) SMBusReadByte() (byte, error) {
+	return 0, nil
+}
+
+// SMBusWriteByte is a no-op that always succeeds, for the same reason as
+// SMBusReadByte.
+func (d *Device) SMBusWriteByte(value byte) error {
+	return nil
+}
+
+// SMBusReadWordData reads a little endian word from the handler for reg.
+func (d *Device) SMBusReadWordData(reg byte) (uint16, error) {
+	resp, err := d.dispatch(reg, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(resp) < 2 {
+		return 0, fmt.Errorf("mocki2c: handler for reg 0x%0X returned %d bytes, want 2", reg, len(resp))
+	}
+
+	return uint16(resp[0]) | uint16(resp[1])<<8, nil
+}
+
+// SMBusWriteWordData writes a little endian word to the handler for reg.
+func (d *Device) SMBusWriteWordData(reg byte, value uint16) error {
+	_, err := d.dispatch(reg, []byte{byte(value), byte(value >> 8)})
+	return err
+}
+
+// SMBusReadBlockData returns whatever block the handler for reg produces.
+func (d *Device) SMBusReadBlockData(reg byte) ([]byte, error) {
+	return d.dispatch(reg, nil)
+}
+
+// SMBusWriteBlockData passes p to the handler for reg.
+func (d *Device) SMBusWriteBlockData(reg byte, p []byte) error {
+	_, err := d.dispatch(reg, p)
+	return err
+}
+
+// SMBusReadI2CBlockData returns up to length bytes from the handler for
+// reg.
+func (d *Device) SMBusReadI2CBlockData(reg byte, length uint8) ([]byte, error) {
+	resp, err := d.dispatch(reg, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp) > int(length) {
+		resp = resp[:length]
+	}
+
+	return resp, nil
+}
+
+// SMBusWriteI2CBlockData passes p to the handler for reg.
+func (d *Device) SMBusWriteI2CBlockData(reg byte, p []byte) error {
+	_, err := d.dispatch(reg, p)
+	return err
+}
+
+// SMBusProcessCall writes value to the handler for reg and returns the
+// little endian word the handler responds with.
+func (d *Device) SMBusProcessCall(reg byte, value uint16) (uint16, error) {
+	resp, err := d.dispatch(reg, []byte{byte(value), byte(value >> 8)})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(resp) < 2 {
+		return 0, fmt.Errorf("mocki2c: handler for reg 0x%0X returned %d bytes, want 2", reg, len(resp))
+	}
+
+	return uint16(resp[0]) | uint16(resp[1])<<8, nil
+}
+
+// SMBusBlockProcessCall writes p to the handler for reg and returns
+// whatever block the handler responds with.
+func (d *Device) SMBusBlockProcessCall(reg byte, p []byte) ([]byte, error) {
+	return d.dispatch(reg, p)
+}
+
+// assert that Device satisfies i2c.I2C.
+var _ i2c.I2C = (*Device)(nil)
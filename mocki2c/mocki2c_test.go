@@ -0,0 +1,135 @@
+package mocki2c
+
+import (
+	"bytes"
+	"testing"
+
+	i2c "github.com/theckman/go-i2c"
+)
+
+func TestRegisterHandler(t *testing.T) {
+	d := New(1, 0x42)
+	d.RegisterHandler(0x10, func(write []byte) []byte {
+		return []byte{0xAB}
+	})
+
+	got, err := d.ReadRegU8(0x10)
+	if err != nil {
+		t.Fatalf("ReadRegU8: %v", err)
+	}
+
+	if got != 0xAB {
+		t.Fatalf("ReadRegU8 = 0x%0X, want 0xAB", got)
+	}
+}
+
+func TestReadRegNoHandler(t *testing.T) {
+	d := New(1, 0x42)
+
+	if _, err := d.ReadRegU8(0x10); err == nil {
+		t.Fatal("ReadRegU8: expected error for unregistered register, got nil")
+	}
+}
+
+func TestRegisterMaskedHandlerPriority(t *testing.T) {
+	d := New(1, 0x42)
+
+	d.RegisterMaskedHandler(0x00, 0xF0, func(write []byte) []byte {
+		return []byte{0x01}
+	})
+	d.RegisterHandler(0x05, func(write []byte) []byte {
+		return []byte{0x02}
+	})
+
+	got, err := d.ReadRegU8(0x05)
+	if err != nil {
+		t.Fatalf("ReadRegU8: %v", err)
+	}
+
+	if got != 0x02 {
+		t.Fatalf("ReadRegU8 = 0x%0X, want 0x02 (exact handler should win over masked)", got)
+	}
+
+	got, err = d.ReadRegU8(0x08)
+	if err != nil {
+		t.Fatalf("ReadRegU8: %v", err)
+	}
+
+	if got != 0x01 {
+		t.Fatalf("ReadRegU8 = 0x%0X, want 0x01 (masked handler)", got)
+	}
+}
+
+func TestCallCount(t *testing.T) {
+	d := New(1, 0x42)
+	d.RegisterHandler(0x10, func(write []byte) []byte {
+		return []byte{0x00}
+	})
+
+	if n := d.CallCount(0x10); n != 0 {
+		t.Fatalf("CallCount before any access = %d, want 0", n)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := d.ReadRegU8(0x10); err != nil {
+			t.Fatalf("ReadRegU8: %v", err)
+		}
+	}
+
+	if n := d.CallCount(0x10); n != 3 {
+		t.Fatalf("CallCount after 3 reads = %d, want 3", n)
+	}
+}
+
+func TestTx(t *testing.T) {
+	d := New(1, 0x42)
+
+	var written []byte
+	d.RegisterHandler(0x20, func(write []byte) []byte {
+		written = append([]byte(nil), write...)
+		return nil
+	})
+	d.RegisterHandler(0x21, func(write []byte) []byte {
+		return []byte{0xCA, 0xFE}
+	})
+
+	wbuf := []byte{0x20, 0x99}
+	rwbuf := []byte{0x21}
+	rbuf := make([]byte, 2)
+
+	msgs := []i2c.Message{
+		{Addr: 0x42, Buf: wbuf},
+		{Addr: 0x42, Buf: rwbuf},
+		{Addr: 0x42, Flags: i2c.Read, Buf: rbuf},
+	}
+
+	if err := d.Tx(msgs); err != nil {
+		t.Fatalf("Tx: %v", err)
+	}
+
+	if !bytes.Equal(written, []byte{0x99}) {
+		t.Fatalf("handler saw write %v, want [0x99]", written)
+	}
+
+	if !bytes.Equal(rbuf, []byte{0xCA, 0xFE}) {
+		t.Fatalf("Tx read %v, want [0xCA 0xFE]", rbuf)
+	}
+}
+
+func TestSetFuncs(t *testing.T) {
+	d := New(1, 0x42)
+	d.SetFuncs(i2c.FuncSMBusReadByte | i2c.FuncTenBitAddr)
+
+	funcs, err := d.Funcs()
+	if err != nil {
+		t.Fatalf("Funcs: %v", err)
+	}
+
+	if !funcs.Has(i2c.FuncSMBusReadByte) {
+		t.Fatal("Funcs missing FuncSMBusReadByte")
+	}
+
+	if funcs.Has(i2c.FuncSMBusWriteByte) {
+		t.Fatal("Funcs reports FuncSMBusWriteByte that was never set")
+	}
+}